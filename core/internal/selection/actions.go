@@ -0,0 +1,88 @@
+package selection
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+func (s *selection) UploadFile(path string) error {
+	element, err := s.getSingleElement(true)
+	if err != nil {
+		return withOp("UploadFile", err)
+	}
+
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return s.newError("UploadFile", ErrUnknown, fmt.Errorf("failed to find absolute path for file %q: %s", path, err))
+	}
+
+	if err := element.Value(absolutePath); err != nil {
+		return s.newError("UploadFile", ErrDriver, err)
+	}
+	return nil
+}
+
+func (s *selection) DragTo(target Selection) error {
+	targetSelection, ok := target.(*selection)
+	if !ok {
+		return s.newError("DragTo", ErrWrongElementType, fmt.Errorf("target is not a valid selection"))
+	}
+
+	source, err := s.getSingleElement(true)
+	if err != nil {
+		return withOp("DragTo", err)
+	}
+
+	destination, err := targetSelection.getSingleElement(true)
+	if err != nil {
+		return withOp("DragTo", err)
+	}
+
+	if err := s.driver.MoveTo(source, nil); err != nil {
+		return s.newError("DragTo", ErrDriver, err)
+	}
+
+	if err := s.driver.ButtonDown(); err != nil {
+		return s.newError("DragTo", ErrDriver, err)
+	}
+
+	if err := s.driver.MoveTo(destination, nil); err != nil {
+		return s.newError("DragTo", ErrDriver, err)
+	}
+
+	if err := s.driver.ButtonUp(); err != nil {
+		return s.newError("DragTo", ErrDriver, err)
+	}
+
+	return nil
+}
+
+func (s *selection) SendKeys(keys ...Key) error {
+	element, err := s.getSingleElement(true)
+	if err != nil {
+		return withOp("SendKeys", err)
+	}
+
+	sequence := ""
+	for _, key := range keys {
+		sequence += string(key)
+	}
+	sequence += string(KeyNull)
+
+	if err := element.Value(sequence); err != nil {
+		return s.newError("SendKeys", ErrDriver, err)
+	}
+	return nil
+}
+
+func (s *selection) Hover() error {
+	element, err := s.getSingleElement(true)
+	if err != nil {
+		return withOp("Hover", err)
+	}
+
+	if err := s.driver.MoveTo(element, nil); err != nil {
+		return s.newError("Hover", ErrDriver, err)
+	}
+	return nil
+}
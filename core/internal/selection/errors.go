@@ -0,0 +1,100 @@
+package selection
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies why a Selection method failed, so callers can
+// distinguish "element not found" from "driver crashed" without parsing
+// error strings.
+type ErrorKind int
+
+const (
+	// ErrUnknown is the zero value, used when no more specific kind applies.
+	ErrUnknown ErrorKind = iota
+	// ErrNotFound means no element matched the selector.
+	ErrNotFound
+	// ErrAmbiguous means more than one element matched the selector where
+	// exactly one was required.
+	ErrAmbiguous
+	// ErrNotInteractable means an element matched but was not visible or
+	// not enabled.
+	ErrNotInteractable
+	// ErrWrongElementType means an element matched but was not of the
+	// type the method required (e.g. Check on a non-checkbox).
+	ErrWrongElementType
+	// ErrDriver means the underlying WebDriver call itself failed.
+	ErrDriver
+	// ErrTimeout means the selection's timeout elapsed before the element
+	// or condition resolved.
+	ErrTimeout
+)
+
+// Error is returned by Selection methods. Op names the method that failed
+// (e.g. "Click"), Selector is the full selector trail, Kind classifies the
+// failure, and Err is the underlying cause.
+type Error struct {
+	Op       string
+	Selector string
+	Kind     ErrorKind
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to %s selector '%s': %s", e.Op, e.Selector, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (s *selection) newError(op string, kind ErrorKind, err error) *Error {
+	return &Error{Op: op, Selector: s.Selector(), Kind: kind, Err: err}
+}
+
+// withOp returns err with its Op set to op, if err is a *Error; otherwise
+// it returns err unchanged.
+func withOp(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var selErr *Error
+	if errors.As(err, &selErr) {
+		copied := *selErr
+		copied.Op = op
+		return &copied
+	}
+	return err
+}
+
+func hasKind(err error, kind ErrorKind) bool {
+	var selErr *Error
+	if errors.As(err, &selErr) {
+		return selErr.Kind == kind
+	}
+	return false
+}
+
+// IsNotFound reports whether err indicates that no element matched.
+func IsNotFound(err error) bool { return hasKind(err, ErrNotFound) }
+
+// IsAmbiguous reports whether err indicates more than one element matched.
+func IsAmbiguous(err error) bool { return hasKind(err, ErrAmbiguous) }
+
+// IsNotInteractable reports whether err indicates the element was found
+// but not visible or not enabled.
+func IsNotInteractable(err error) bool { return hasKind(err, ErrNotInteractable) }
+
+// IsWrongElementType reports whether err indicates the element was found
+// but was the wrong type for the operation.
+func IsWrongElementType(err error) bool { return hasKind(err, ErrWrongElementType) }
+
+// IsDriverError reports whether err originated from the underlying
+// WebDriver call rather than from selection logic.
+func IsDriverError(err error) bool { return hasKind(err, ErrDriver) }
+
+// IsTimeout reports whether err indicates the selection's timeout
+// elapsed before it resolved.
+func IsTimeout(err error) bool { return hasKind(err, ErrTimeout) }
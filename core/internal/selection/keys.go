@@ -0,0 +1,21 @@
+package selection
+
+// Key is a single keystroke for SendKeys. Ordinary characters can be cast
+// directly (selection.Key("a")); the constants below are the special and
+// modifier keys, encoded as WebDriver expects - one rune from the Unicode
+// private-use area per the WebDriver JSON wire protocol.
+type Key string
+
+const (
+	KeyNull      Key = ""
+	KeyBackspace Key = ""
+	KeyTab       Key = ""
+	KeyEnter     Key = ""
+	KeyShift     Key = ""
+	KeyControl   Key = ""
+	KeyAlt       Key = ""
+	KeyEscape    Key = ""
+	KeySpace     Key = ""
+	KeyDelete    Key = ""
+	KeyCommand   Key = ""
+)
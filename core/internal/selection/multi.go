@@ -0,0 +1,68 @@
+package selection
+
+// At narrows the selection to the element at index. A negative index
+// counts back from the last matched element.
+func (s *selection) At(index int) Selection {
+	return &selection{driver: s.driver, parent: s.parent, self: s.self, timeout: s.timeout, index: &index, filter: s.filter}
+}
+
+// First narrows the selection to its first matched element.
+func (s *selection) First() Selection {
+	return s.At(0)
+}
+
+// Last narrows the selection to its last matched element.
+func (s *selection) Last() Selection {
+	return s.At(-1)
+}
+
+// Filter narrows the selection to the elements for which predicate
+// returns true. Filters compose: calling Filter again narrows further.
+func (s *selection) Filter(predicate func(Selection) (bool, error)) Selection {
+	previous := s.filter
+	combined := func(candidate Selection) (bool, error) {
+		if previous != nil {
+			ok, err := previous(candidate)
+			if err != nil || !ok {
+				return ok, err
+			}
+		}
+		return predicate(candidate)
+	}
+	return &selection{driver: s.driver, parent: s.parent, self: s.self, timeout: s.timeout, index: s.index, filter: combined}
+}
+
+// All resolves every element currently matched by the selection and
+// returns a Selection bound to each one.
+func (s *selection) All() ([]Selection, error) {
+	elements, err := s.resolveElements()
+	if err != nil {
+		return nil, withOp("All", err)
+	}
+
+	selections := make([]Selection, len(elements))
+	for i := range elements {
+		index := i
+		if s.index != nil {
+			index = *s.index
+		}
+		selections[i] = &selection{driver: s.driver, parent: s.parent, self: s.self, timeout: s.timeout, index: &index, filter: s.filter}
+	}
+	return selections, nil
+}
+
+// Each calls fn with the index and Selection of every element currently
+// matched by the selection, stopping at the first error fn returns.
+func (s *selection) Each(fn func(index int, selection Selection) error) error {
+	selections, err := s.All()
+	if err != nil {
+		return withOp("Each", err)
+	}
+
+	for i, selection := range selections {
+		if err := fn(i, selection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,13 +1,24 @@
 package selection
 
 import (
+	"errors"
 	"fmt"
 	"github.com/sclevine/agouti/core/internal/webdriver"
 	"strings"
+	"time"
 )
 
+// defaultPollInterval is how often a waiting selection re-polls the driver
+// while a timeout is in effect.
+const defaultPollInterval = 100 * time.Millisecond
+
 type Selection interface {
 	Find(selector string) Selection
+	FindByXPath(xpath string) Selection
+	FindByLinkText(text string) Selection
+	FindByPartialLinkText(text string) Selection
+	FindByLabel(text string) Selection
+	FindByScript(script string, args ...interface{}) Selection
 	Selector() string
 	Count() (int, error)
 	Click() error
@@ -22,80 +33,172 @@ type Selection interface {
 	Visible() (bool, error)
 	Select(text string) error
 	Submit() error
+
+	// UploadFile sets a file input's value to the absolute path of path,
+	// so that a subsequent Submit uploads it.
+	UploadFile(path string) error
+	// DragTo drags this selection's element onto target's element.
+	DragTo(target Selection) error
+	// SendKeys sends a sequence of keystrokes, including modifiers and
+	// special keys, to the selected element.
+	SendKeys(keys ...Key) error
+	// Hover moves the mouse over the selected element.
+	Hover() error
+
+	// At narrows the selection to the element at index (negative counts
+	// back from the end, as in Last).
+	At(index int) Selection
+	First() Selection
+	Last() Selection
+	// All resolves every element currently matched by the selection.
+	All() ([]Selection, error)
+	// Each calls fn for every element currently matched by the selection.
+	Each(fn func(index int, selection Selection) error) error
+	// Filter narrows the selection to the elements for which predicate
+	// returns true.
+	Filter(predicate func(Selection) (bool, error)) Selection
+
+	// WithTimeout returns a copy of the selection that polls for up to the
+	// given duration when an element or condition is not yet satisfied,
+	// instead of failing immediately.
+	WithTimeout(timeout time.Duration) Selection
+
+	// WaitFor polls the selection until predicate returns true, or the
+	// selection's timeout elapses.
+	WaitFor(predicate func(Selection) (bool, error)) error
+	WaitForVisible() error
+	WaitForHidden() error
+	WaitForEnabled() error
+	WaitForCount(n int) error
 }
 
 type selection struct {
-	driver    driver
-	selectors []string
+	driver driver
+	// parent is the selection this selection's own selector resolves
+	// against, honoring the parent's index/filter narrowing (nil for the
+	// root selection, whose selector resolves against the driver's root
+	// context).
+	parent  *selection
+	self    Selector
+	timeout time.Duration
+	index   *int
+	filter  func(Selection) (bool, error)
 }
 
 type driver interface {
-	GetElements(selector string) ([]webdriver.Element, error)
+	GetElementsBy(selector Selector, parent webdriver.Element) ([]webdriver.Element, error)
 	DoubleClick() error
 	MoveTo(element webdriver.Element, point webdriver.Point) error
+	ButtonDown() error
+	ButtonUp() error
 }
 
 func New(driver driver, selector string) Selection {
-	return &selection{driver, []string{selector}}
+	return &selection{driver: driver, self: ByCSS(selector)}
 }
 
 func (s *selection) Find(selector string) Selection {
-	return &selection{s.driver, append(s.selectors, selector)}
+	return s.findBy(ByCSS(selector))
+}
+
+func (s *selection) FindByXPath(xpath string) Selection {
+	return s.findBy(ByXPath(xpath))
+}
+
+func (s *selection) FindByLinkText(text string) Selection {
+	return s.findBy(ByLinkText(text))
+}
+
+func (s *selection) FindByPartialLinkText(text string) Selection {
+	return s.findBy(ByPartialLinkText(text))
+}
+
+func (s *selection) FindByLabel(text string) Selection {
+	return s.findBy(ByLabel(text))
+}
+
+func (s *selection) FindByScript(script string, args ...interface{}) Selection {
+	return s.findBy(ByJS(script, args...))
+}
+
+func (s *selection) findBy(selector Selector) Selection {
+	return &selection{driver: s.driver, parent: s, self: selector, timeout: s.timeout}
 }
 
 func (s *selection) Selector() string {
-	return strings.Join(s.selectors, " ")
+	return strings.Join(s.trail(), " >> ")
+}
+
+// trail renders this selection's own hop - and its index/filter narrowing,
+// if any - onto its parent's trail.
+func (s *selection) trail() []string {
+	var trail []string
+	if s.parent != nil {
+		trail = s.parent.trail()
+	}
+	trail = append(trail, s.self.String())
+	if s.index != nil {
+		trail = append(trail, fmt.Sprintf("at(%d)", *s.index))
+	}
+	if s.filter != nil {
+		trail = append(trail, "filter")
+	}
+	return trail
+}
+
+func (s *selection) WithTimeout(timeout time.Duration) Selection {
+	return &selection{s.driver, s.parent, s.self, timeout, s.index, s.filter}
 }
 
 func (s *selection) Count() (int, error) {
-	elements, err := s.driver.GetElements(s.Selector())
+	elements, err := s.resolveElements()
 	if err != nil {
-		return 0, fmt.Errorf("failed to retrieve elements for selector '%s': %s", s.Selector(), err)
+		return 0, withOp("Count", err)
 	}
 
 	return len(elements), nil
 }
 
 func (s *selection) Click() error {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(true)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return withOp("Click", err)
 	}
 
 	if err := element.Click(); err != nil {
-		return fmt.Errorf("failed to click on selector '%s': %s", s.Selector(), err)
+		return s.newError("Click", ErrDriver, err)
 	}
 	return nil
 }
 
 func (s *selection) DoubleClick() error {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(true)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return withOp("DoubleClick", err)
 	}
 
 	if err := s.driver.MoveTo(element, nil); err != nil {
-		return fmt.Errorf("failed to move mouse to selector '%s': %s", s.Selector(), err)
+		return s.newError("DoubleClick", ErrDriver, err)
 	}
 
 	if err := s.driver.DoubleClick(); err != nil {
-		return fmt.Errorf("failed to double-click on selector '%s': %s", s.Selector(), err)
+		return s.newError("DoubleClick", ErrDriver, err)
 	}
 	return nil
 }
 
 func (s *selection) Fill(text string) error {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(true)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return withOp("Fill", err)
 	}
 
 	if err := element.Clear(); err != nil {
-		return fmt.Errorf("failed to clear selector '%s': %s", s.Selector(), err)
+		return s.newError("Fill", ErrDriver, err)
 	}
 
 	if err := element.Value(text); err != nil {
-		return fmt.Errorf("failed to enter text into selector '%s': %s", s.Selector(), err)
+		return s.newError("Fill", ErrDriver, err)
 	}
 	return nil
 }
@@ -109,28 +212,33 @@ func (s *selection) Uncheck() error {
 }
 
 func (s *selection) setChecked(checked bool) error {
-	element, err := s.getSingleElement()
+	op := "Check"
+	if !checked {
+		op = "Uncheck"
+	}
+
+	element, err := s.getSingleElement(true)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return withOp(op, err)
 	}
 
 	elementType, err := element.GetAttribute("type")
 	if err != nil {
-		return fmt.Errorf("failed to retrieve type of selector '%s': %s", s.Selector(), err)
+		return s.newError(op, ErrDriver, err)
 	}
 
 	if elementType != "checkbox" {
-		return fmt.Errorf("selector '%s' does not refer to a checkbox", s.Selector())
+		return s.newError(op, ErrWrongElementType, fmt.Errorf("selector does not refer to a checkbox"))
 	}
 
 	selected, err := element.IsSelected()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve state of selector '%s': %s", s.Selector(), err)
+		return s.newError(op, ErrDriver, err)
 	}
 
 	if selected != checked {
 		if err := element.Click(); err != nil {
-			return fmt.Errorf("failed to click selector '%s': %s", s.Selector(), err)
+			return s.newError(op, ErrDriver, err)
 		}
 	}
 
@@ -138,119 +246,331 @@ func (s *selection) setChecked(checked bool) error {
 }
 
 func (s *selection) Text() (string, error) {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(false)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return "", withOp("Text", err)
 	}
 
 	text, err := element.GetText()
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve text for selector '%s': %s", s.Selector(), err)
+		return "", s.newError("Text", ErrDriver, err)
 	}
 	return text, nil
 }
 
 func (s *selection) Attribute(attribute string) (string, error) {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(false)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return "", withOp("Attribute", err)
 	}
 
 	value, err := element.GetAttribute(attribute)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve attribute value for selector '%s': %s", s.Selector(), err)
+		return "", s.newError("Attribute", ErrDriver, err)
 	}
 	return value, nil
 }
 
 func (s *selection) CSS(property string) (string, error) {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(false)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return "", withOp("CSS", err)
 	}
 
 	value, err := element.GetCSS(property)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve CSS property for selector '%s': %s", s.Selector(), err)
+		return "", s.newError("CSS", ErrDriver, err)
 	}
 	return value, nil
 }
 
 func (s *selection) Selected() (bool, error) {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(false)
 	if err != nil {
-		return false, fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return false, withOp("Selected", err)
 	}
 
 	selected, err := element.IsSelected()
 	if err != nil {
-		return false, fmt.Errorf("failed to determine whether selector '%s' is selected: %s", s.Selector(), err)
+		return false, s.newError("Selected", ErrDriver, err)
 	}
 
 	return selected, nil
 }
 
 func (s *selection) Visible() (bool, error) {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(false)
 	if err != nil {
-		return false, fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return false, withOp("Visible", err)
 	}
 
 	visible, err := element.IsDisplayed()
 	if err != nil {
-		return false, fmt.Errorf("failed to determine whether selector '%s' is visible: %s", s.Selector(), err)
+		return false, s.newError("Visible", ErrDriver, err)
 	}
 
 	return visible, nil
 }
 
 func (s *selection) Select(text string) error {
-	elements, err := s.driver.GetElements(s.Selector() + " option")
+	element, err := s.getSingleElement(true)
+	if err != nil {
+		return withOp("Select", err)
+	}
+
+	elements, err := s.driver.GetElementsBy(ByCSS("option"), element)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve options for selector '%s': %s", s.Selector(), err)
+		return s.newError("Select", ErrDriver, err)
 	}
 
 	for _, element := range elements {
 		elementText, err := element.GetText()
 		if err != nil {
-			return fmt.Errorf("failed to retrieve option text for selector '%s': %s", s.Selector(), err)
+			return s.newError("Select", ErrDriver, err)
 		}
 
 		if elementText == text {
 			if err := element.Click(); err != nil {
-				return fmt.Errorf(`failed to click on option with text "%s" for selector '%s': %s`, elementText, s.Selector(), err)
+				return s.newError("Select", ErrDriver, err)
 			}
 			return nil
 		}
 	}
 
-	return fmt.Errorf(`no options with text "%s" found for selector '%s'`, text, s.Selector())
+	return s.newError("Select", ErrNotFound, fmt.Errorf("no option with text %q", text))
 }
 
 func (s *selection) Submit() error {
-	element, err := s.getSingleElement()
+	element, err := s.getSingleElement(true)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve element with selector '%s': %s", s.Selector(), err)
+		return withOp("Submit", err)
 	}
 
 	if err := element.Submit(); err != nil {
-		return fmt.Errorf("failed to submit selector '%s': %s", s.Selector(), err)
+		return s.newError("Submit", ErrDriver, err)
 	}
 	return nil
 }
 
-func (s *selection) getSingleElement() (webdriver.Element, error) {
-	elements, err := s.driver.GetElements(s.Selector())
+// getSingleElement resolves the selection to exactly one element, polling
+// the driver every defaultPollInterval until it succeeds or the
+// selection's timeout elapses. Without a timeout it makes a single attempt
+// and returns that attempt's error. When actionable is true, the element must
+// also be present, visible, and not disabled before it is returned -
+// this is the precondition the interaction methods (Click, Fill, Check,
+// Submit, Select) require.
+func (s *selection) getSingleElement(actionable bool) (webdriver.Element, error) {
+	deadline := time.Now().Add(s.timeout)
+
+	var lastErr error
+	for {
+		element, err := s.resolveSingleElement()
+		if err == nil {
+			if !actionable {
+				return element, nil
+			}
+
+			ready, reason, err := s.checkActionable(element)
+			if err != nil {
+				return nil, err
+			}
+			if ready {
+				return element, nil
+			}
+			lastErr = s.newError("", ErrNotInteractable, fmt.Errorf("element found but not %s", reason))
+		} else {
+			lastErr = err
+		}
+
+		if s.timeout <= 0 {
+			return nil, lastErr
+		}
+		if time.Now().After(deadline) {
+			return nil, s.newError("", ErrTimeout, lastErr)
+		}
+		time.Sleep(defaultPollInterval)
+	}
+}
+
+func (s *selection) resolveSingleElement() (webdriver.Element, error) {
+	elements, err := s.resolveElements()
 	if err != nil {
 		return nil, err
 	}
 
 	if len(elements) > 1 {
-		return nil, fmt.Errorf("mutiple elements (%d) were selected", len(elements))
+		return nil, s.newError("", ErrAmbiguous, fmt.Errorf("%d elements were selected", len(elements)))
 	}
 	if len(elements) == 0 {
-		return nil, fmt.Errorf("no element found")
+		return nil, s.newError("", ErrNotFound, fmt.Errorf("no element found"))
 	}
 
 	return elements[0], nil
 }
+
+// resolveElements resolves this selection's own hop against its resolved
+// parent element, then narrows the result by the selection's filter and
+// index, if any are set.
+func (s *selection) resolveElements() ([]webdriver.Element, error) {
+	parent, err := s.resolveParentElement()
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := s.driver.GetElementsBy(s.self, parent)
+	if err != nil {
+		return nil, s.newError("", ErrDriver, err)
+	}
+
+	elements, err = s.applyFilter(elements)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyIndex(elements)
+}
+
+// resolveParentElement resolves this selection's parent to the single
+// element this selection's own selector is evaluated against, honoring any
+// index/filter narrowing applied to the parent. A nil parent (the root
+// selection) resolves to the driver's root context.
+func (s *selection) resolveParentElement() (webdriver.Element, error) {
+	if s.parent == nil {
+		return nil, nil
+	}
+	return s.parent.resolveSingleElement()
+}
+
+func (s *selection) applyFilter(elements []webdriver.Element) ([]webdriver.Element, error) {
+	if s.filter == nil {
+		return elements, nil
+	}
+
+	var filtered []webdriver.Element
+	for i := range elements {
+		index := i
+		candidate := &selection{driver: s.driver, parent: s.parent, self: s.self, timeout: s.timeout, index: &index}
+		ok, err := s.filter(candidate)
+		if err != nil {
+			return nil, s.newError("", ErrUnknown, err)
+		}
+		if ok {
+			filtered = append(filtered, elements[i])
+		}
+	}
+	return filtered, nil
+}
+
+func (s *selection) applyIndex(elements []webdriver.Element) ([]webdriver.Element, error) {
+	if s.index == nil {
+		return elements, nil
+	}
+
+	index := *s.index
+	if index < 0 {
+		index += len(elements)
+	}
+	if index < 0 || index >= len(elements) {
+		return nil, s.newError("", ErrNotFound, fmt.Errorf("index %d out of range for %d matched elements", *s.index, len(elements)))
+	}
+	return elements[index : index+1], nil
+}
+
+func (s *selection) checkActionable(element webdriver.Element) (ready bool, reason string, err error) {
+	visible, visErr := element.IsDisplayed()
+	if visErr != nil {
+		return false, "", s.newError("", ErrDriver, visErr)
+	}
+	if !visible {
+		return false, "visible", nil
+	}
+
+	enabled, enErr := element.IsEnabled()
+	if enErr != nil {
+		return false, "", s.newError("", ErrDriver, enErr)
+	}
+	if !enabled {
+		return false, "enabled", nil
+	}
+
+	return true, "", nil
+}
+
+// WaitFor polls predicate, passing this selection, until it returns true
+// or the selection's timeout elapses.
+func (s *selection) WaitFor(predicate func(Selection) (bool, error)) error {
+	return s.waitFor("WaitFor", "satisfy condition", func() (bool, error) {
+		return predicate(s)
+	})
+}
+
+func (s *selection) WaitForVisible() error {
+	return s.waitFor("WaitForVisible", "visible", func() (bool, error) {
+		element, err := s.resolveSingleElement()
+		if err != nil {
+			return false, nil
+		}
+		visible, err := element.IsDisplayed()
+		if err != nil {
+			return false, nil
+		}
+		return visible, nil
+	})
+}
+
+func (s *selection) WaitForHidden() error {
+	return s.waitFor("WaitForHidden", "hidden", func() (bool, error) {
+		element, err := s.resolveSingleElement()
+		if err != nil {
+			return true, nil
+		}
+		visible, err := element.IsDisplayed()
+		if err != nil {
+			return true, nil
+		}
+		return !visible, nil
+	})
+}
+
+func (s *selection) WaitForEnabled() error {
+	return s.waitFor("WaitForEnabled", "enabled", func() (bool, error) {
+		element, err := s.resolveSingleElement()
+		if err != nil {
+			return false, nil
+		}
+		return element.IsEnabled()
+	})
+}
+
+func (s *selection) WaitForCount(n int) error {
+	return s.waitFor("WaitForCount", fmt.Sprintf("have count %d", n), func() (bool, error) {
+		count, err := s.Count()
+		if err != nil {
+			return false, err
+		}
+		return count == n, nil
+	})
+}
+
+func (s *selection) waitFor(op, condition string, check func() (bool, error)) error {
+	deadline := time.Now().Add(s.timeout)
+
+	for {
+		ok, err := check()
+		if err != nil {
+			var selErr *Error
+			if errors.As(err, &selErr) {
+				return withOp(op, err)
+			}
+			return s.newError(op, ErrUnknown, fmt.Errorf("failed to check whether selector is %s: %s", condition, err))
+		}
+		if ok {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return s.newError(op, ErrTimeout, fmt.Errorf("timed out waiting to be %s", condition))
+		}
+		time.Sleep(defaultPollInterval)
+	}
+}
@@ -0,0 +1,199 @@
+package selection
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sclevine/agouti/core/internal/webdriver"
+)
+
+// fakeElement is a minimal webdriver.Element stand-in for exercising
+// Selection logic without a real driver or browser.
+type fakeElement struct {
+	text      string
+	attrs     map[string]string
+	css       map[string]string
+	selected  bool
+	displayed bool
+	enabled   bool
+	clicks    int
+	value     string
+}
+
+func (e *fakeElement) Click() error                             { e.clicks++; return nil }
+func (e *fakeElement) Clear() error                             { return nil }
+func (e *fakeElement) Value(value string) error                 { e.value = value; return nil }
+func (e *fakeElement) GetAttribute(name string) (string, error) { return e.attrs[name], nil }
+func (e *fakeElement) IsSelected() (bool, error)                { return e.selected, nil }
+func (e *fakeElement) GetText() (string, error)                 { return e.text, nil }
+func (e *fakeElement) GetCSS(name string) (string, error)       { return e.css[name], nil }
+func (e *fakeElement) IsDisplayed() (bool, error)               { return e.displayed, nil }
+func (e *fakeElement) IsEnabled() (bool, error)                 { return e.enabled, nil }
+func (e *fakeElement) Submit() error                            { return nil }
+
+func elements(es ...*fakeElement) []webdriver.Element {
+	result := make([]webdriver.Element, len(es))
+	for i, e := range es {
+		result[i] = e
+	}
+	return result
+}
+
+// fakeDriver resolves selectors via a callback, so each test can describe
+// only the hops it cares about.
+type fakeDriver struct {
+	getElementsBy func(sel Selector, parent webdriver.Element) ([]webdriver.Element, error)
+}
+
+func (d *fakeDriver) GetElementsBy(sel Selector, parent webdriver.Element) ([]webdriver.Element, error) {
+	return d.getElementsBy(sel, parent)
+}
+func (d *fakeDriver) DoubleClick() error                                            { return nil }
+func (d *fakeDriver) MoveTo(element webdriver.Element, point webdriver.Point) error { return nil }
+func (d *fakeDriver) ButtonDown() error                                             { return nil }
+func (d *fakeDriver) ButtonUp() error                                               { return nil }
+
+func TestSelectHonorsAtNarrowing(t *testing.T) {
+	selects := []*fakeElement{
+		{displayed: true, enabled: true},
+		{displayed: true, enabled: true},
+		{displayed: true, enabled: true},
+	}
+	options := map[*fakeElement][]*fakeElement{
+		selects[1]: {{text: "Option A"}, {text: "Option B"}},
+	}
+
+	driver := &fakeDriver{
+		getElementsBy: func(sel Selector, parent webdriver.Element) ([]webdriver.Element, error) {
+			switch sel.Value {
+			case "select":
+				return elements(selects...), nil
+			case "option":
+				parentElement := parent.(*fakeElement)
+				return elements(options[parentElement]...), nil
+			default:
+				return nil, fmt.Errorf("unexpected selector %q", sel.Value)
+			}
+		},
+	}
+
+	if err := New(driver, "select").At(1).Select("Option B"); err != nil {
+		t.Fatalf("Select returned an unexpected error: %s", err)
+	}
+
+	if clicks := options[selects[1]][1].clicks; clicks != 1 {
+		t.Errorf("expected the option at index 1 to be clicked once, got %d clicks", clicks)
+	}
+	if clicks := options[selects[1]][0].clicks; clicks != 0 {
+		t.Errorf("expected the option not named by Select to remain unclicked, got %d clicks", clicks)
+	}
+}
+
+func TestFindDrillsIntoNarrowedParent(t *testing.T) {
+	rows := []*fakeElement{{}, {}, {}}
+	cells := map[*fakeElement]*fakeElement{
+		rows[1]: {text: "Row 1 Cell"},
+	}
+
+	driver := &fakeDriver{
+		getElementsBy: func(sel Selector, parent webdriver.Element) ([]webdriver.Element, error) {
+			switch sel.Value {
+			case "tr":
+				return elements(rows...), nil
+			case "td":
+				row := parent.(*fakeElement)
+				cell, ok := cells[row]
+				if !ok {
+					return nil, nil
+				}
+				return elements(cell), nil
+			default:
+				return nil, fmt.Errorf("unexpected selector %q", sel.Value)
+			}
+		},
+	}
+
+	text, err := New(driver, "tr").At(1).Find("td").Text()
+	if err != nil {
+		t.Fatalf("Text returned an unexpected error: %s", err)
+	}
+	if text != "Row 1 Cell" {
+		t.Errorf("Text() = %q, want %q", text, "Row 1 Cell")
+	}
+}
+
+func TestSelectorIncludesAtAndFilter(t *testing.T) {
+	driver := &fakeDriver{
+		getElementsBy: func(sel Selector, parent webdriver.Element) ([]webdriver.Element, error) {
+			return nil, fmt.Errorf("selector resolution not expected in this test")
+		},
+	}
+
+	at := New(driver, "select").At(2)
+	if got, want := at.Selector(), `css:select >> at(2)`; got != want {
+		t.Errorf("Selector() = %q, want %q", got, want)
+	}
+
+	filtered := New(driver, "tr").Filter(func(Selection) (bool, error) { return true, nil })
+	if got, want := filtered.Selector(), `css:tr >> filter`; got != want {
+		t.Errorf("Selector() = %q, want %q", got, want)
+	}
+
+	partial := New(driver, "body").FindByPartialLinkText("Sign")
+	if got, want := partial.Selector(), `css:body >> partial-link:"Sign"`; got != want {
+		t.Errorf("Selector() = %q, want %q", got, want)
+	}
+}
+
+func TestClickPollsUntilActionable(t *testing.T) {
+	target := &fakeElement{}
+	calls := 0
+
+	driver := &fakeDriver{
+		getElementsBy: func(sel Selector, parent webdriver.Element) ([]webdriver.Element, error) {
+			calls++
+			if calls < 3 {
+				return nil, nil
+			}
+			target.displayed = true
+			target.enabled = true
+			return elements(target), nil
+		},
+	}
+
+	err := New(driver, "button").WithTimeout(time.Second).Click()
+	if err != nil {
+		t.Fatalf("Click returned an unexpected error: %s", err)
+	}
+	if target.clicks != 1 {
+		t.Errorf("expected the element to be clicked once, got %d clicks", target.clicks)
+	}
+	if calls < 3 {
+		t.Errorf("expected Click to poll until the element was actionable, only polled %d times", calls)
+	}
+}
+
+func TestWaitForCountForwardsTypedError(t *testing.T) {
+	driver := &fakeDriver{
+		getElementsBy: func(sel Selector, parent webdriver.Element) ([]webdriver.Element, error) {
+			if sel.Value == "div" {
+				return elements(&fakeElement{}, &fakeElement{}), nil
+			}
+			return nil, fmt.Errorf("unexpected selector %q", sel.Value)
+		},
+	}
+
+	err := New(driver, "div").Find("span").WithTimeout(50 * time.Millisecond).WaitForCount(1)
+	if !IsAmbiguous(err) {
+		t.Fatalf("expected WaitForCount to surface the ambiguous ancestor as a typed error, got %v", err)
+	}
+
+	selErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if selErr.Op != "WaitForCount" {
+		t.Errorf("expected Op %q, got %q", "WaitForCount", selErr.Op)
+	}
+}
@@ -0,0 +1,62 @@
+package selection
+
+import "fmt"
+
+// Selector identifies how a single hop of a Find chain should be resolved -
+// by CSS, XPath, link text, label text, or an arbitrary script. Each hop in
+// a chain may use a different engine.
+type Selector struct {
+	Using string
+	Value string
+	Args  []interface{}
+}
+
+// ByCSS selects elements using a CSS selector. This is the engine Find uses.
+func ByCSS(css string) Selector {
+	return Selector{Using: "css", Value: css}
+}
+
+// ByXPath selects elements using an XPath expression.
+func ByXPath(xpath string) Selector {
+	return Selector{Using: "xpath", Value: xpath}
+}
+
+// ByLinkText selects anchor elements with the given exact link text.
+func ByLinkText(text string) Selector {
+	return Selector{Using: "link text", Value: text}
+}
+
+// ByPartialLinkText selects anchor elements whose link text contains text.
+func ByPartialLinkText(text string) Selector {
+	return Selector{Using: "partial link text", Value: text}
+}
+
+// ByLabel selects the form control associated with a <label> containing text.
+func ByLabel(text string) Selector {
+	return Selector{Using: "label", Value: text}
+}
+
+// ByJS selects elements by running script, which must return an element or
+// an array of elements, with args passed through as script arguments.
+func ByJS(script string, args ...interface{}) Selector {
+	return Selector{Using: "js", Value: script, Args: args}
+}
+
+func (sel Selector) String() string {
+	switch sel.Using {
+	case "css":
+		return fmt.Sprintf("css:%s", sel.Value)
+	case "xpath":
+		return fmt.Sprintf("xpath:%s", sel.Value)
+	case "link text":
+		return fmt.Sprintf("link:%q", sel.Value)
+	case "partial link text":
+		return fmt.Sprintf("partial-link:%q", sel.Value)
+	case "label":
+		return fmt.Sprintf("label:%q", sel.Value)
+	case "js":
+		return fmt.Sprintf("js:%s", sel.Value)
+	default:
+		return sel.Value
+	}
+}